@@ -0,0 +1,37 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"strings"
+)
+
+// FindCycle searches the graph, starting from its roots, for a cycle.  If one is found, the vertices along it are
+// returned in edge order, with the first vertex repeated at the end so that the path reads naturally (e.g., for a
+// diagnostic like "A -> B -> A").  If no cycle is reachable from the roots, FindCycle returns nil.  This is the same
+// cycle that Topsort reports in its error.
+func FindCycle(g Graph) []Vertex {
+	_, cycle := topsort(g)
+	return cycle
+}
+
+// cyclePath renders a cycle returned by FindCycle as a path of vertex labels (e.g., "A -> B -> A").
+func cyclePath(cycle []Vertex) string {
+	var labels []string
+	for _, v := range cycle {
+		labels = append(labels, v.Label())
+	}
+	return strings.Join(labels, " -> ")
+}
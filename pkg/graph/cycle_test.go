@@ -0,0 +1,110 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEdge struct {
+	from *testVertex
+	to   *testVertex
+}
+
+func (e *testEdge) Data() interface{} { return nil }
+func (e *testEdge) Label() string     { return "" }
+func (e *testEdge) To() Vertex        { return e.to }
+func (e *testEdge) From() Vertex      { return e.from }
+func (e *testEdge) Color() string     { return "" }
+
+type testVertex struct {
+	label string
+	ins   []Edge
+	outs  []Edge
+}
+
+func (v *testVertex) Data() interface{} { return nil }
+func (v *testVertex) Label() string     { return v.label }
+func (v *testVertex) Ins() []Edge       { return v.ins }
+func (v *testVertex) Outs() []Edge      { return v.outs }
+
+// testGraph treats every vertex as a root, in the order in which they were added.
+type testGraph struct {
+	vertices []*testVertex
+}
+
+func (g *testGraph) Roots() []Edge {
+	var roots []Edge
+	for _, v := range g.vertices {
+		roots = append(roots, &testEdge{to: v})
+	}
+	return roots
+}
+
+func (g *testGraph) vertex(label string) *testVertex {
+	for _, v := range g.vertices {
+		if v.label == label {
+			return v
+		}
+	}
+	v := &testVertex{label: label}
+	g.vertices = append(g.vertices, v)
+	return v
+}
+
+// newTestGraph builds a graph from a list of edges, each of the form {from, to}.
+func newTestGraph(edges ...[2]string) *testGraph {
+	g := &testGraph{}
+	for _, e := range edges {
+		from, to := g.vertex(e[0]), g.vertex(e[1])
+		edge := &testEdge{from: from, to: to}
+		from.outs = append(from.outs, edge)
+		to.ins = append(to.ins, edge)
+	}
+	return g
+}
+
+func labels(vs []Vertex) []string {
+	var ls []string
+	for _, v := range vs {
+		ls = append(ls, v.Label())
+	}
+	return ls
+}
+
+func TestFindCycleAcyclic(t *testing.T) {
+	t.Parallel()
+
+	// A diamond shares a vertex between two paths, but is not a cycle.
+	g := newTestGraph([2]string{"a", "b"}, [2]string{"a", "c"}, [2]string{"b", "d"}, [2]string{"c", "d"})
+	assert.Nil(t, FindCycle(g))
+}
+
+func TestFindCycleSelf(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGraph([2]string{"a", "a"})
+	assert.Equal(t, []string{"a", "a"}, labels(FindCycle(g)))
+}
+
+func TestFindCycleIndirect(t *testing.T) {
+	t.Parallel()
+
+	// The cycle does not include the vertex we entered from, so it must be trimmed off of the path.
+	g := newTestGraph([2]string{"a", "b"}, [2]string{"b", "c"}, [2]string{"c", "d"}, [2]string{"d", "b"})
+	assert.Equal(t, []string{"b", "c", "d", "b"}, labels(FindCycle(g)))
+}
@@ -16,38 +16,64 @@ package graph
 
 import (
 	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
 // Topsort topologically sorts the graph, yielding an array of nodes that are in dependency order, using a simple
-// DFS-based algorithm.  The graph must be acyclic, otherwise this function will return an error.
+// DFS-based algorithm.  The result is deterministic so long as the graph's roots and edges are enumerated in a stable
+// order.  The graph must be acyclic, otherwise this function will return an error that describes the offending cycle.
 func Topsort(g Graph) ([]Vertex, error) {
+	sorted, cycle := topsort(g)
+	if cycle != nil {
+		return sorted, errors.Errorf("Graph is not a DAG: %v", cyclePath(cycle))
+	}
+	return sorted, nil
+}
+
+// topsort performs the DFS behind Topsort and FindCycle.  It returns the vertices sorted so far and, if the sort
+// stopped because it found a cycle, the vertices along that cycle (see FindCycle).
+func topsort(g Graph) ([]Vertex, []Vertex) {
 	var sorted []Vertex               // will hold the sorted vertices.
+	var path []Vertex                 // the vertices on the current DFS path, to report cycles.
 	visiting := make(map[Vertex]bool) // temporary entries to detect cycles.
 	visited := make(map[Vertex]bool)  // entries to avoid visiting the same node twice.
 
 	// Now enumerate the roots, topologically sorting their dependencies.
 	roots := g.Roots()
 	for _, r := range roots {
-		if err := topvisit(r.To(), &sorted, visiting, visited); err != nil {
-			return sorted, err
+		if cycle := topvisit(r.To(), &sorted, &path, visiting, visited); cycle != nil {
+			return sorted, cycle
 		}
 	}
 	return sorted, nil
 }
 
-func topvisit(n Vertex, sorted *[]Vertex, visiting map[Vertex]bool, visited map[Vertex]bool) error {
+func topvisit(n Vertex, sorted *[]Vertex, path *[]Vertex, visiting map[Vertex]bool,
+	visited map[Vertex]bool) []Vertex {
 	if visiting[n] {
-		// This is not a DAG!  Stop sorting right away, and issue an error.
-		// IDEA: return diagnostic information about why this isn't a DAG (e.g., full cycle path).
-		return errors.New("Graph is not a DAG")
+		// This is not a DAG!  Stop sorting right away; n is on the current path, so everything from its position
+		// onward, closed off by n itself, is the cycle we return.
+		start := -1
+		for i, v := range *path {
+			if v == n {
+				start = i
+				break
+			}
+		}
+		contract.Assertf(start != -1, "Vertex '%v' is being visited but is missing from the current path", n.Label())
+		cycle := append([]Vertex{}, (*path)[start:]...)
+		return append(cycle, n)
 	}
 	if !visited[n] {
 		visiting[n] = true
+		*path = append(*path, n)
 		for _, m := range n.Outs() {
-			if err := topvisit(m.To(), sorted, visiting, visited); err != nil {
-				return err
+			if cycle := topvisit(m.To(), sorted, path, visiting, visited); cycle != nil {
+				return cycle
 			}
 		}
+		*path = (*path)[:len(*path)-1]
 		visited[n] = true
 		visiting[n] = false
 		*sorted = append(*sorted, n)
@@ -0,0 +1,47 @@
+// Copyright 2016-2018, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopsort(t *testing.T) {
+	t.Parallel()
+
+	// Each vertex's outgoing edges point at its dependencies, so those must come first.
+	g := newTestGraph([2]string{"app", "db"}, [2]string{"app", "cache"}, [2]string{"db", "network"},
+		[2]string{"cache", "network"})
+	sorted, err := Topsort(g)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"network", "db", "cache", "app"}, labels(sorted))
+
+	// Sorting the same graph again yields the same order.
+	again, err := Topsort(g)
+	assert.Nil(t, err)
+	assert.Equal(t, labels(sorted), labels(again))
+}
+
+func TestTopsortCycle(t *testing.T) {
+	t.Parallel()
+
+	g := newTestGraph([2]string{"a", "b"}, [2]string{"b", "c"}, [2]string{"c", "a"})
+	_, err := Topsort(g)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "Graph is not a DAG: a -> b -> c -> a", err.Error())
+	}
+}
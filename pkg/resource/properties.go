@@ -18,6 +18,10 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/pulumi/pulumi/pkg/tokens"
 	"github.com/pulumi/pulumi/pkg/util/contract"
@@ -107,6 +111,67 @@ func (m PropertyMap) HasValue(k PropertyKey) bool {
 	return has && v.HasValue()
 }
 
+// GetString returns the string value of the given key.  The present flag is false if the key is missing or null; an
+// error is returned if the key has a value that isn't a string.
+func (m PropertyMap) GetString(k PropertyKey) (string, bool, error) {
+	v, present, err := m.get(k, "string", PropertyValue.IsString)
+	if !present || err != nil {
+		return "", present, err
+	}
+	return v.StringValue(), true, nil
+}
+
+// GetNumber returns the number value of the given key.  The present flag is false if the key is missing or null; an
+// error is returned if the key has a value that isn't a number.
+func (m PropertyMap) GetNumber(k PropertyKey) (float64, bool, error) {
+	v, present, err := m.get(k, "number", PropertyValue.IsNumber)
+	if !present || err != nil {
+		return 0, present, err
+	}
+	return v.NumberValue(), true, nil
+}
+
+// GetBool returns the bool value of the given key.  The present flag is false if the key is missing or null; an error
+// is returned if the key has a value that isn't a bool.
+func (m PropertyMap) GetBool(k PropertyKey) (bool, bool, error) {
+	v, present, err := m.get(k, "bool", PropertyValue.IsBool)
+	if !present || err != nil {
+		return false, present, err
+	}
+	return v.BoolValue(), true, nil
+}
+
+// GetArray returns the array value of the given key.  The present flag is false if the key is missing or null; an
+// error is returned if the key has a value that isn't an array.
+func (m PropertyMap) GetArray(k PropertyKey) ([]PropertyValue, bool, error) {
+	v, present, err := m.get(k, "[]", PropertyValue.IsArray)
+	if !present || err != nil {
+		return nil, present, err
+	}
+	return v.ArrayValue(), true, nil
+}
+
+// GetMap returns the object value of the given key.  The present flag is false if the key is missing or null; an
+// error is returned if the key has a value that isn't an object.
+func (m PropertyMap) GetMap(k PropertyKey) (PropertyMap, bool, error) {
+	v, present, err := m.get(k, "object", PropertyValue.IsObject)
+	if !present || err != nil {
+		return nil, present, err
+	}
+	return v.ObjectValue(), true, nil
+}
+
+// get looks up the given key, checking that its value, if any, has the expected type (named as in TypeString).
+func (m PropertyMap) get(k PropertyKey, typ string, is func(PropertyValue) bool) (PropertyValue, bool, error) {
+	v, has := m[k]
+	if !has || v.IsNull() {
+		return v, false, nil
+	} else if !is(v) {
+		return v, true, errors.Errorf("property '%v' has a value of type %v, not %v", k, v.TypeString(), typ)
+	}
+	return v, true, nil
+}
+
 // ContainsUnknowns returns true if the property map contains at least one unknown value.
 func (m PropertyMap) ContainsUnknowns() bool {
 	for _, v := range m {
@@ -139,6 +204,214 @@ func (m PropertyMap) MapRepl(replk func(string) (string, bool),
 	return obj
 }
 
+// Flatten returns a flat map from property paths to this map's leaf values.  Nested object properties are addressed
+// with a "." (e.g., "a.b") and array elements with a "[i]" suffix (e.g., "a[0]").  A key that is empty or contains
+// path syntax (".", "[" or "]") is instead written as a quoted string in brackets (e.g., `tags["app.io/name"]`).
+// Empty objects and arrays are kept as leaves, so that Unflatten can losslessly reconstruct the original map.
+func (m PropertyMap) Flatten() map[string]PropertyValue {
+	flat := make(map[string]PropertyValue)
+	for k, v := range m {
+		v.flatten(appendFlatKey("", k), flat)
+	}
+	return flat
+}
+
+func (v PropertyValue) flatten(path string, flat map[string]PropertyValue) {
+	if v.IsObject() && len(v.ObjectValue()) > 0 {
+		for k, e := range v.ObjectValue() {
+			e.flatten(appendFlatKey(path, k), flat)
+		}
+	} else if v.IsArray() && len(v.ArrayValue()) > 0 {
+		for i, e := range v.ArrayValue() {
+			e.flatten(fmt.Sprintf("%s[%d]", path, i), flat)
+		}
+	} else {
+		flat[path] = v
+	}
+}
+
+// appendFlatKey appends the key k to a flattened property path, quoting it if it can't be written as a plain key.
+func appendFlatKey(path string, k PropertyKey) string {
+	if k == "" || strings.ContainsAny(string(k), ".[]") {
+		return path + "[" + strconv.Quote(string(k)) + "]"
+	} else if path == "" {
+		return string(k)
+	}
+	return path + "." + string(k)
+}
+
+// Unflatten is the inverse of PropertyMap.Flatten: it rebuilds a property map from a flat map of property paths to
+// leaf values.  An error is returned if a path is malformed, if two paths disagree about whether a property is an
+// object, an array, or a leaf, or if an array's indices are not contiguous from zero.
+func Unflatten(flat map[string]PropertyValue) (PropertyMap, error) {
+	root := &flatNode{}
+	for path, v := range flat {
+		segs, err := parseFlatPath(path)
+		if err != nil {
+			return nil, err
+		}
+		n := root
+		for _, seg := range segs {
+			if n, err = n.child(seg, path); err != nil {
+				return nil, err
+			}
+		}
+		if n.leaf != nil || n.object != nil || n.array != nil {
+			return nil, errors.Errorf("property path '%v' conflicts with another path", path)
+		}
+		leaf := v
+		n.leaf = &leaf
+	}
+	obj, err := root.value()
+	if err != nil {
+		return nil, err
+	}
+	return obj.ObjectValue(), nil
+}
+
+// flatNode is an intermediate tree used by Unflatten to assemble values before array bounds are known.
+type flatNode struct {
+	leaf   *PropertyValue
+	object map[PropertyKey]*flatNode
+	array  map[int]*flatNode
+}
+
+// child returns the child of this node addressed by seg (a PropertyKey or an int array index), creating it if needed.
+func (n *flatNode) child(seg interface{}, path string) (*flatNode, error) {
+	if n.leaf != nil {
+		return nil, errors.Errorf("property path '%v' conflicts with another path", path)
+	}
+	switch s := seg.(type) {
+	case PropertyKey:
+		if n.array != nil {
+			return nil, errors.Errorf("property path '%v' uses a key where another path uses an index", path)
+		}
+		if n.object == nil {
+			n.object = make(map[PropertyKey]*flatNode)
+		}
+		if n.object[s] == nil {
+			n.object[s] = &flatNode{}
+		}
+		return n.object[s], nil
+	case int:
+		if n.object != nil {
+			return nil, errors.Errorf("property path '%v' uses an index where another path uses a key", path)
+		}
+		if n.array == nil {
+			n.array = make(map[int]*flatNode)
+		}
+		if n.array[s] == nil {
+			n.array[s] = &flatNode{}
+		}
+		return n.array[s], nil
+	default:
+		contract.Failf("unexpected flattened path segment %v", seg)
+		return nil, nil
+	}
+}
+
+func (n *flatNode) value() (PropertyValue, error) {
+	if n.leaf != nil {
+		return *n.leaf, nil
+	} else if n.array != nil {
+		arr := make([]PropertyValue, len(n.array))
+		for i := range arr {
+			e, has := n.array[i]
+			if !has {
+				return PropertyValue{}, errors.Errorf("array is missing element %v", i)
+			}
+			v, err := e.value()
+			if err != nil {
+				return PropertyValue{}, err
+			}
+			arr[i] = v
+		}
+		return NewArrayProperty(arr), nil
+	}
+	obj := make(PropertyMap)
+	for k, e := range n.object {
+		v, err := e.value()
+		if err != nil {
+			return PropertyValue{}, err
+		}
+		obj[k] = v
+	}
+	return NewObjectProperty(obj), nil
+}
+
+// parseFlatPath splits a flattened property path into its segments: a PropertyKey for each key and an int for each
+// array index.  The path must begin with a key, either plain or quoted.
+func parseFlatPath(path string) ([]interface{}, error) {
+	var segs []interface{}
+	rest := path
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, `["`):
+			// A quoted key runs up to the first unescaped quote, which must be followed by the closing bracket.
+			end := 2
+			for end < len(rest) && rest[end] != '"' {
+				if rest[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end+1 >= len(rest) || rest[end+1] != ']' {
+				return nil, errors.Errorf("property path '%v' has an unterminated quoted key", path)
+			}
+			key, err := strconv.Unquote(rest[1 : end+1])
+			if err != nil {
+				return nil, errors.Errorf("property path '%v' has an invalid quoted key %v", path, rest[1:end+1])
+			}
+			segs = append(segs, PropertyKey(key))
+			rest = rest[end+2:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 || len(segs) == 0 {
+				return nil, errors.Errorf("property path '%v' is malformed", path)
+			}
+			ix, ok := parseFlatIndex(rest[1:end])
+			if !ok {
+				return nil, errors.Errorf("property path '%v' has an invalid array index '%v'", path, rest[1:end])
+			}
+			segs = append(segs, ix)
+			rest = rest[end+1:]
+		case len(segs) > 0 && rest[0] != '.':
+			return nil, errors.Errorf("property path '%v' is malformed", path)
+		default:
+			if len(segs) > 0 {
+				rest = rest[1:] // skip the "." that precedes every plain key but the first.
+			}
+			end := strings.IndexAny(rest, ".[]")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, errors.Errorf("property path '%v' has an empty key", path)
+			}
+			segs = append(segs, PropertyKey(rest[:end]))
+			rest = rest[end:]
+		}
+	}
+	if len(segs) == 0 {
+		return nil, errors.New("property path is empty")
+	}
+	return segs, nil
+}
+
+// parseFlatIndex parses an array index as written by Flatten: plain decimal digits, without a sign or leading zeros.
+func parseFlatIndex(s string) (int, bool) {
+	if s == "" || (s[0] == '0' && len(s) > 1) {
+		return 0, false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	ix, err := strconv.Atoi(s)
+	return ix, err == nil
+}
+
 // Copy makes a shallow copy of the map.
 func (m PropertyMap) Copy() PropertyMap {
 	new := make(PropertyMap)
@@ -148,6 +421,16 @@ func (m PropertyMap) Copy() PropertyMap {
 	return new
 }
 
+// DeepCopy makes a deep copy of the map, such that no objects, arrays, assets, or archives are shared with the
+// original.  Unlike Copy, this makes it safe to mutate nested values in either map without affecting the other.
+func (m PropertyMap) DeepCopy() PropertyMap {
+	new := make(PropertyMap)
+	for k, v := range m {
+		new[k] = v.DeepCopy()
+	}
+	return new
+}
+
 // Merge simply merges in another map atop another, and returns the result.
 func (m PropertyMap) Merge(other PropertyMap) PropertyMap {
 	new := m.Copy()
@@ -430,6 +713,50 @@ func (v PropertyValue) TypeString() string {
 	return ""
 }
 
+// DeepCopy makes a deep copy of the value, such that no objects, arrays, assets, or archives are shared with the
+// original.  Scalars are immutable and are simply returned as-is.
+func (v PropertyValue) DeepCopy() PropertyValue {
+	if v.IsArray() {
+		arr := make([]PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = e.DeepCopy()
+		}
+		return NewArrayProperty(arr)
+	} else if v.IsObject() {
+		return NewObjectProperty(v.ObjectValue().DeepCopy())
+	} else if v.IsAsset() {
+		a := *v.AssetValue()
+		return NewAssetProperty(&a)
+	} else if v.IsArchive() {
+		return NewArchiveProperty(copyArchive(v.ArchiveValue()))
+	} else if v.IsComputed() {
+		return NewComputedProperty(Computed{Element: v.Input().Element.DeepCopy()})
+	} else if v.IsOutput() {
+		return NewOutputProperty(Output{Element: v.OutputValue().Element.DeepCopy()})
+	}
+	return v
+}
+
+// copyArchive makes a deep copy of an archive, including any assets and archives that it contains.
+func copyArchive(arch *Archive) *Archive {
+	a := *arch
+	if arch.Assets != nil {
+		a.Assets = make(map[string]interface{})
+		for k, e := range arch.Assets {
+			switch t := e.(type) {
+			case *Asset:
+				c := *t
+				a.Assets[k] = &c
+			case *Archive:
+				a.Assets[k] = copyArchive(t)
+			default:
+				a.Assets[k] = e
+			}
+		}
+	}
+	return &a
+}
+
 // Mappable returns a mapper-compatible value, suitable for deserialization into structures.
 func (v PropertyValue) Mappable() interface{} {
 	return v.MapRepl(nil, nil)
@@ -143,6 +143,168 @@ func TestMapReplComputedOutput(t *testing.T) {
 	assert.Equal(t, m, m2)
 }
 
+func TestTypedGetters(t *testing.T) {
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"s": "str",
+		"n": float64(42),
+		"b": true,
+		"a": []interface{}{"x"},
+		"o": map[string]interface{}{"k": "v"},
+		"z": nil,
+	})
+
+	s, present, err := m.GetString("s")
+	assert.Equal(t, "str", s)
+	assert.True(t, present)
+	assert.Nil(t, err)
+	n, present, err := m.GetNumber("n")
+	assert.Equal(t, float64(42), n)
+	assert.True(t, present)
+	assert.Nil(t, err)
+	b, present, err := m.GetBool("b")
+	assert.True(t, b)
+	assert.True(t, present)
+	assert.Nil(t, err)
+	a, present, err := m.GetArray("a")
+	assert.Equal(t, []PropertyValue{NewStringProperty("x")}, a)
+	assert.True(t, present)
+	assert.Nil(t, err)
+	o, present, err := m.GetMap("o")
+	assert.Equal(t, PropertyMap{"k": NewStringProperty("v")}, o)
+	assert.True(t, present)
+	assert.Nil(t, err)
+
+	// Missing and null keys are simply absent.
+	for _, k := range []PropertyKey{"missing", "z"} {
+		_, present, err = m.GetString(k)
+		assert.False(t, present)
+		assert.Nil(t, err)
+		_, present, err = m.GetMap(k)
+		assert.False(t, present)
+		assert.Nil(t, err)
+	}
+
+	// Present keys of the wrong type are errors, rather than panics.
+	_, present, err = m.GetString("n")
+	assert.True(t, present)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "property 'n' has a value of type number, not string", err.Error())
+	}
+	_, present, err = m.GetNumber("s")
+	assert.True(t, present)
+	assert.NotNil(t, err)
+	_, present, err = m.GetBool("a")
+	assert.True(t, present)
+	assert.NotNil(t, err)
+	_, present, err = m.GetArray("o")
+	assert.True(t, present)
+	assert.NotNil(t, err)
+	_, present, err = m.GetMap("b")
+	assert.True(t, present)
+	assert.NotNil(t, err)
+}
+
+func TestFlatten(t *testing.T) {
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"a": "str",
+		"b": map[string]interface{}{
+			"c": float64(42),
+			"d": map[string]interface{}{
+				"e": true,
+			},
+		},
+		"f": []interface{}{
+			"x",
+			map[string]interface{}{"g": "y"},
+			[]interface{}{float64(1), float64(2)},
+		},
+	})
+	flat := m.Flatten()
+	assert.Equal(t, map[string]PropertyValue{
+		"a":       NewStringProperty("str"),
+		"b.c":     NewNumberProperty(42),
+		"b.d.e":   NewBoolProperty(true),
+		"f[0]":    NewStringProperty("x"),
+		"f[1].g":  NewStringProperty("y"),
+		"f[2][0]": NewNumberProperty(1),
+		"f[2][1]": NewNumberProperty(2),
+	}, flat)
+
+	// Unflattening must give back exactly what we started with.
+	m2, err := Unflatten(flat)
+	assert.Nil(t, err)
+	assert.Equal(t, m, m2)
+}
+
+func TestFlattenRoundTripLeaves(t *testing.T) {
+	// Empty objects and arrays, nulls, and computed values are all leaves, and must survive a round-trip intact.
+	m := PropertyMap{
+		"a": NewObjectProperty(PropertyMap{}),
+		"b": NewArrayProperty([]PropertyValue{}),
+		"c": NewArrayProperty([]PropertyValue{NewNullProperty(), NewObjectProperty(PropertyMap{})}),
+		"d": NewComputedProperty(Computed{Element: NewStringProperty("X")}),
+	}
+	flat := m.Flatten()
+	assert.Equal(t, 5, len(flat))
+	m2, err := Unflatten(flat)
+	assert.Nil(t, err)
+	assert.Equal(t, m, m2)
+}
+
+func TestFlattenQuotedKeys(t *testing.T) {
+	// Keys that contain path syntax, like Kubernetes labels, are quoted rather than split apart.
+	m := NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{
+			"app.kubernetes.io/name": "x",
+			"a[0]":                   "y",
+			"":                       "z",
+			`say "hi"`:               "w",
+		},
+		"e.1": []interface{}{map[string]interface{}{"e.n": float64(1)}},
+	})
+	flat := m.Flatten()
+	assert.Equal(t, map[string]PropertyValue{
+		`tags["app.kubernetes.io/name"]`: NewStringProperty("x"),
+		`tags["a[0]"]`:                   NewStringProperty("y"),
+		`tags[""]`:                       NewStringProperty("z"),
+		`tags.say "hi"`:                  NewStringProperty("w"),
+		`["e.1"][0]["e.n"]`:              NewNumberProperty(1),
+	}, flat)
+
+	m2, err := Unflatten(flat)
+	assert.Nil(t, err)
+	assert.Equal(t, m, m2)
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	var badFlats = []map[string]PropertyValue{
+		{"": NewStringProperty("x")},                                    // empty path.
+		{"[0]": NewStringProperty("x")},                                 // paths must start with a key.
+		{"a.": NewStringProperty("x")},                                  // empty key.
+		{"a..b": NewStringProperty("x")},                                // ditto.
+		{"a[0]b": NewStringProperty("x")},                               // keys after an index need a ".".
+		{"a[x]": NewStringProperty("x")},                                // non-numeric index.
+		{"a[-1]": NewStringProperty("x")},                               // negative index.
+		{"a[0": NewStringProperty("x")},                                 // unterminated index.
+		{"a[+0]": NewStringProperty("x")},                               // signed index.
+		{"a[00]": NewStringProperty("x")},                               // leading zeros.
+		{"a[01]": NewStringProperty("x")},                               // ditto.
+		{"a[]": NewStringProperty("x")},                                 // missing index.
+		{`a["b`: NewStringProperty("x")},                                // unterminated quoted key.
+		{`a["b"`: NewStringProperty("x")},                               // ditto.
+		{`a["b\"]`: NewStringProperty("x")},                             // ditto, since the quote is escaped.
+		{`a["\q"]`: NewStringProperty("x")},                             // invalid escape.
+		{`a["b"]c`: NewStringProperty("x")},                             // keys after a quoted key need a ".".
+		{"a[1]": NewStringProperty("x")},                                // indices must start at zero.
+		{"a": NewStringProperty("x"), "a.b": NewStringProperty("y")},    // a is both a leaf and an object.
+		{"a.b": NewStringProperty("x"), "a[0]": NewStringProperty("y")}, // a is both an object and an array.
+	}
+	for _, flat := range badFlats {
+		_, err := Unflatten(flat)
+		assert.NotNil(t, err, "Unflatten expected to fail: %v", flat)
+	}
+}
+
 func TestCopy(t *testing.T) {
 	src := NewPropertyMapFromMap(map[string]interface{}{
 		"a": "str",
@@ -158,3 +320,41 @@ func TestCopy(t *testing.T) {
 	src["c"] = NewNumberProperty(99.99)
 	assert.Equal(t, 2, len(dst))
 }
+
+func TestDeepCopy(t *testing.T) {
+	asset, err := NewTextAsset("hello")
+	assert.Nil(t, err)
+	archive, err := NewAssetArchive(map[string]interface{}{"a": asset})
+	assert.Nil(t, err)
+	src := NewPropertyMapFromMap(map[string]interface{}{
+		"a": "str",
+		"b": map[string]interface{}{
+			"c": []interface{}{float64(1), map[string]interface{}{"d": true}},
+		},
+		"e": asset,
+		"f": archive,
+	})
+	src["g"] = NewComputedProperty(Computed{Element: NewObjectProperty(PropertyMap{"h": NewStringProperty("x")})})
+	golden := NewPropertyMapFromMap(src.Mappable())
+	dst := src.DeepCopy()
+	assert.Equal(t, src, dst)
+
+	// Mutate the source at every level; the copy must not observe any of it.
+	src["a"] = NewNullProperty()
+	b := src["b"].ObjectValue()
+	c := b["c"].ArrayValue()
+	c[0] = NewNumberProperty(99)
+	c[1].ObjectValue()["d"] = NewBoolProperty(false)
+	b["new"] = NewStringProperty("added")
+	src["e"].AssetValue().Text = "goodbye"
+	src["f"].ArchiveValue().Assets["a"].(*Asset).Text = "goodbye"
+	src["f"].ArchiveValue().Assets["z"] = asset
+	src["g"].Input().Element.ObjectValue()["h"] = NewStringProperty("y")
+
+	assert.Equal(t, NewStringProperty("str"), dst["a"])
+	assert.Equal(t, golden["b"], dst["b"])
+	assert.Equal(t, "hello", dst["e"].AssetValue().Text)
+	assert.Equal(t, 1, len(dst["f"].ArchiveValue().Assets))
+	assert.Equal(t, "hello", dst["f"].ArchiveValue().Assets["a"].(*Asset).Text)
+	assert.Equal(t, NewStringProperty("x"), dst["g"].Input().Element.ObjectValue()["h"])
+}
@@ -18,6 +18,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	"github.com/pulumi/pulumi/pkg/util/contract"
 )
 
@@ -66,6 +68,22 @@ func AsQName(s string) QName {
 	return QName(s)
 }
 
+// ParseQName attempts to turn the string s into a QName, returning an error that names the first offending element if
+// any of its "/"-delimited parts isn't a legal Name.
+func ParseQName(s string) (QName, error) {
+	if s == "" {
+		return "", errors.New("QName is empty")
+	}
+	for _, elem := range strings.Split(s, QNameDelimiter) {
+		if elem == "" {
+			return "", errors.Errorf("QName '%v' has an empty element", s)
+		} else if !IsName(elem) {
+			return "", errors.Errorf("QName '%v' has an illegal element '%v' (expected %v)", s, elem, NameRegexpPattern)
+		}
+	}
+	return QName(s), nil
+}
+
 // Name extracts the Name portion of a QName (dropping any namespace).
 func (nm QName) Name() Name {
 	ix := strings.LastIndex(string(nm), QNameDelimiter)
@@ -82,16 +100,24 @@ func (nm QName) Name() Name {
 // Namespace extracts the namespace portion of a QName (dropping the name); this may be empty.
 func (nm QName) Namespace() QName {
 	ix := strings.LastIndex(string(nm), QNameDelimiter)
-	var qn string
 	if ix == -1 {
-		qn = ""
-	} else {
-		qn = string(nm[:ix])
+		return ""
 	}
+	qn := string(nm[:ix])
 	contract.Assert(IsQName(qn))
 	return QName(qn)
 }
 
+// Qualify prefixes this QName with the given namespace; if the namespace is empty, the QName is returned unchanged.
+func (nm QName) Qualify(ns QName) QName {
+	if ns == "" {
+		return nm
+	}
+	qn := ns + QNameDelimiter + nm
+	contract.Assertf(IsQName(string(qn)), "Qualifying '%v' with namespace '%v' produced an illegal name", nm, ns)
+	return qn
+}
+
 // PackageName is a qualified name referring to an imported package.  It is similar to a QName, except that it permits
 // dashes "-" as is commonplace with packages of various kinds.
 type PackageName string
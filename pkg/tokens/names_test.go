@@ -62,6 +62,34 @@ func TestIsAsName(t *testing.T) {
 	}
 }
 
+func TestParseQName(t *testing.T) {
+	t.Parallel()
+
+	qn, err := ParseQName("ns1/ns2/complex")
+	assert.Nil(t, err)
+	assert.Equal(t, "ns1/ns2/complex", string(qn))
+
+	// Empty names and empty elements are rejected, and otherwise the first illegal element is reported.
+	var badQNames = []struct {
+		Name    string
+		Message string
+	}{
+		{"", "QName is empty"},
+		{"0complex", "QName '0complex' has an illegal element '0complex'"},
+		{"namespace/0complex", "QName 'namespace/0complex' has an illegal element '0complex'"},
+		{"namesp@ce/compl#x", "QName 'namesp@ce/compl#x' has an illegal element 'namesp@ce'"},
+		{"namespace//complex", "QName 'namespace//complex' has an empty element"},
+		{"namespace/complex/", "QName 'namespace/complex/' has an empty element"},
+		{"/complex", "QName '/complex' has an empty element"},
+	}
+	for _, bad := range badQNames {
+		_, err := ParseQName(bad.Name)
+		if assert.NotNil(t, err, "ParseQName expected to fail: %v", bad.Name) {
+			assert.Contains(t, err.Error(), bad.Message)
+		}
+	}
+}
+
 func TestNameSimple(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "simple", string(AsName("simple")))
@@ -75,4 +103,21 @@ func TestNameNamespace(t *testing.T) {
 	assert.Equal(t, "namespace", string(AsQName("namespace/complex").Namespace()))
 	assert.Equal(t, "ns1/ns2/ns3/ns4", string(AsQName("ns1/ns2/ns3/ns4/complex").Namespace()))
 	assert.Equal(t, "_/_/_/_/a0", string(AsQName("_/_/_/_/a0/c0Mpl3x_").Namespace()))
+	assert.Equal(t, "", string(AsQName("simple").Namespace()))
+}
+
+func TestNameQualify(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "simple", string(AsQName("simple").Qualify("")))
+	assert.Equal(t, "namespace/simple", string(AsQName("simple").Qualify("namespace")))
+	assert.Equal(t, "ns1/ns2/ns3/ns4/complex", string(AsQName("ns4/complex").Qualify("ns1/ns2/ns3")))
+
+	// Qualifying with a QName's own namespace round-trips back to the original.
+	qn := AsQName("ns1/ns2/ns3/ns4/complex")
+	assert.Equal(t, qn, qn.Name().Q().Qualify(qn.Namespace()))
+	assert.Equal(t, AsQName("simple"), AsQName("simple").Name().Q().Qualify(AsQName("simple").Namespace()))
+
+	// Qualifying must never produce an illegal name, such as one with an empty element.
+	assert.Panics(t, func() { QName("").Qualify("namespace") })
+	assert.Panics(t, func() { AsQName("simple").Qualify("namespace/") })
 }